@@ -0,0 +1,121 @@
+package dex
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PLACE_ORDERS_BATCH and CANCEL_ORDERS_BATCH extend the socket message
+// router so a client can submit or tear down many orders in a single WS
+// frame instead of round-tripping one message per order.
+const (
+	PLACE_ORDERS_BATCH  MessageType = "PLACE_ORDERS_BATCH"
+	CANCEL_ORDERS_BATCH MessageType = "CANCEL_ORDERS_BATCH"
+)
+
+// DefaultBatchRetryLimit bounds how many times BatchRetryPlaceOrders
+// re-attempts a single failed order before giving up on it.
+const DefaultBatchRetryLimit = 5
+
+// RejectBatchFailed is the ORDER_REJECTED reason code for an order that was
+// still failing after every configured BatchRetryPlaceOrders attempt.
+// BatchRetryPlaceOrders returns per-order errors and does not send it.
+const RejectBatchFailed = "BATCH_RETRY_EXCEEDED"
+
+// BatchPlaceOrders submits orders to the engine one at a time and returns the
+// orders that were admitted to the book alongside a parallel slice of errors
+// (nil for orders that succeeded), so a client submitting a whole rebalance
+// can send one WS frame and receive a per-order result back.
+func (e *Engine) BatchPlaceOrders(ctx context.Context, orders []*Order) ([]*Order, []error) {
+	placed := make([]*Order, len(orders))
+	errs := make([]error, len(orders))
+
+	for i, o := range orders {
+		select {
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		default:
+		}
+
+		if err := e.AddOrder(o); err != nil {
+			errs[i] = err
+			continue
+		}
+		placed[i] = o
+	}
+
+	return placed, errs
+}
+
+// BatchRetryPlaceOrders retries the orders that failed in a BatchPlaceOrders
+// call, backing off exponentially between attempts, until each order either
+// succeeds or maxRetries is exhausted (DefaultBatchRetryLimit when zero).
+func (e *Engine) BatchRetryPlaceOrders(ctx context.Context, orders []*Order, maxRetries int) ([]*Order, []error) {
+	if maxRetries <= 0 {
+		maxRetries = DefaultBatchRetryLimit
+	}
+
+	placed, errs := e.BatchPlaceOrders(ctx, orders)
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		var retryOrders []*Order
+		var retryIdx []int
+
+		for i, err := range errs {
+			if err != nil {
+				retryOrders = append(retryOrders, orders[i])
+				retryIdx = append(retryIdx, i)
+			}
+		}
+
+		if len(retryOrders) == 0 {
+			break
+		}
+
+		backoff := time.Duration(1<<uint(attempt-1)) * 100 * time.Millisecond
+		select {
+		case <-ctx.Done():
+			return placed, errs
+		case <-time.After(backoff):
+		}
+
+		retryPlaced, retryErrs := e.BatchPlaceOrders(ctx, retryOrders)
+		for j, idx := range retryIdx {
+			placed[idx] = retryPlaced[j]
+			errs[idx] = retryErrs[j]
+		}
+	}
+
+	return placed, errs
+}
+
+// GracefulCancelAll walks the active-orders index for pairID and cancels
+// every resting order owned by maker, emitting an ORDER_CANCELED event per
+// order so a market-maker can flatten its own book cleanly on disconnect
+// without touching any other maker's resting orders.
+func (e *Engine) GracefulCancelAll(pairID int64, maker common.Address) error {
+	orders := e.ActiveOrders(pairID)
+
+	for _, o := range orders {
+		if o.Maker != maker {
+			continue
+		}
+
+		oc := &OrderCancel{
+			OrderId:   o.Id,
+			PairID:    o.PairID,
+			OrderHash: o.Hash,
+		}
+
+		if err := e.CancelOrder(oc); err != nil {
+			log.Printf("Error: GracefulCancelAll failed to cancel order %d: %v", o.Id, err)
+			continue
+		}
+	}
+
+	return nil
+}
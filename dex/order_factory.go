@@ -72,6 +72,15 @@ func (f *OrderFactory) SetExchangeAddress(exchange common.Address) error {
 	return nil
 }
 
+// SetPairMetadata registers the pair's trading constraints, typically
+// called once at startup after loading pair config or reading it on-chain.
+// NewOrder rounds amounts down to the tick and lot sizes; it does not check
+// the minimum notional.
+func (f *OrderFactory) SetPairMetadata(m *PairMetadata) error {
+	f.Pair.Metadata = m
+	return nil
+}
+
 func (f *OrderFactory) NewOrderMessage(tokenBuy Token, amountBuy int64, tokenSell Token, amountSell int64) (*Message, *Order, error) {
 	o, err := f.NewOrder(tokenBuy, amountBuy, tokenSell, amountBuy)
 	if err != nil {
@@ -102,6 +111,12 @@ func (f *OrderFactory) NewOrder(tokenBuy Token, amountBuy int64, tokenSell Token
 	o.Price = 0
 	o.Amount = 0
 	o.PairID = f.Pair.ID
+
+	if f.Pair.Metadata != nil {
+		o.AmountBuy = roundDownToTick(o.AmountBuy, f.Pair.Metadata.PriceTickSize)
+		o.AmountSell = roundDownToTick(o.AmountSell, f.Pair.Metadata.AmountTickSize)
+	}
+
 	o.Sign(f.Wallet)
 
 	log.Printf("Order is equal to %v", o)
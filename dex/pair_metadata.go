@@ -0,0 +1,141 @@
+package dex
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+)
+
+// ORDER_REJECTED carries a machine-readable reason code back to the client
+// when an order is rejected before it ever reaches the matching engine.
+const ORDER_REJECTED MessageType = "ORDER_REJECTED"
+
+// Reason codes returned in an OrderRejectedPayload.
+const (
+	RejectTickSize     = "TICK_SIZE"
+	RejectLotSize      = "LOT_SIZE"
+	RejectMinNotional  = "MIN_NOTIONAL"
+	RejectExpired      = "EXPIRED"
+	RejectBadSignature = "BAD_SIGNATURE"
+)
+
+// PairMetadata describes the trading constraints for a TokenPair, populated
+// at startup from a config file or an on-chain call. It mirrors the
+// instrument metadata exposed by mature exchange connectors and keeps dust
+// orders from polluting the book.
+type PairMetadata struct {
+	PriceTickSize  *big.Int
+	AmountTickSize *big.Int
+	MinNotional    *big.Int
+	ContractVal    *big.Int
+}
+
+// OrderRejectedPayload reports why an order never reached the matching
+// engine.
+type OrderRejectedPayload struct {
+	Order  *Order
+	Reason string
+}
+
+// ValidateOrder checks o against the pair's tick-size, lot-size and
+// minimum-notional constraints, returning the reason code for the first
+// violation found, or "" if o satisfies every constraint.
+func (m *PairMetadata) ValidateOrder(o *Order) string {
+	if m == nil {
+		return ""
+	}
+
+	if tickSet(m.AmountTickSize) && !isMultiple(o.AmountSell, m.AmountTickSize) {
+		return RejectLotSize
+	}
+
+	if tickSet(m.PriceTickSize) && !isMultiple(o.AmountBuy, m.PriceTickSize) {
+		return RejectTickSize
+	}
+
+	if tickSet(m.MinNotional) && o.AmountSell.Cmp(m.MinNotional) < 0 {
+		return RejectMinNotional
+	}
+
+	return ""
+}
+
+// PairMetadata returns the trading constraints registered for pairID, or nil
+// if the pair has no metadata configured.
+func (e *Engine) PairMetadata(pairID int64) *PairMetadata {
+	return e.pairMetadata[pairID]
+}
+
+// SetPairMetadata registers the trading constraints for pairID. Called once
+// per pair at startup, either from LoadPairMetadata or after an on-chain
+// call that reads the instrument's tick size, lot size and min notional.
+func (e *Engine) SetPairMetadata(pairID int64, m *PairMetadata) {
+	if e.pairMetadata == nil {
+		e.pairMetadata = make(map[int64]*PairMetadata)
+	}
+	e.pairMetadata[pairID] = m
+}
+
+// pairMetadataEntry is the on-disk shape of one entry in the pair metadata
+// config file loaded by LoadPairMetadata.
+type pairMetadataEntry struct {
+	PairID         int64    `json:"pairId"`
+	PriceTickSize  *big.Int `json:"priceTickSize"`
+	AmountTickSize *big.Int `json:"amountTickSize"`
+	MinNotional    *big.Int `json:"minNotional"`
+	ContractVal    *big.Int `json:"contractVal"`
+}
+
+// LoadPairMetadata reads a JSON array of per-pair metadata from path and
+// registers each entry on the engine. This package has no reader that loads
+// pair metadata from the exchange contract on-chain.
+func (e *Engine) LoadPairMetadata(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var entries []pairMetadataEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		e.SetPairMetadata(entry.PairID, &PairMetadata{
+			PriceTickSize:  entry.PriceTickSize,
+			AmountTickSize: entry.AmountTickSize,
+			MinNotional:    entry.MinNotional,
+			ContractVal:    entry.ContractVal,
+		})
+	}
+
+	return nil
+}
+
+func tickSet(n *big.Int) bool {
+	return n != nil && n.Sign() > 0
+}
+
+// isMultiple reports whether amount is an exact multiple of tick. An unset
+// tick imposes no constraint.
+func isMultiple(amount, tick *big.Int) bool {
+	if !tickSet(tick) {
+		return true
+	}
+	return new(big.Int).Mod(amount, tick).Sign() == 0
+}
+
+// roundDownToTick rounds amount down to the nearest multiple of tick,
+// leaving amount unchanged when tick is unset.
+func roundDownToTick(amount, tick *big.Int) *big.Int {
+	if !tickSet(tick) {
+		return amount
+	}
+
+	rem := new(big.Int).Mod(amount, tick)
+	if rem.Sign() == 0 {
+		return amount
+	}
+
+	return new(big.Int).Sub(amount, rem)
+}
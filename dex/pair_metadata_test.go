@@ -0,0 +1,86 @@
+package dex
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestIsMultiple(t *testing.T) {
+	cases := []struct {
+		name   string
+		amount *big.Int
+		tick   *big.Int
+		want   bool
+	}{
+		{"unset tick imposes no constraint", big.NewInt(7), nil, true},
+		{"zero tick imposes no constraint", big.NewInt(7), big.NewInt(0), true},
+		{"exact multiple", big.NewInt(100), big.NewInt(10), true},
+		{"not a multiple", big.NewInt(105), big.NewInt(10), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isMultiple(c.amount, c.tick); got != c.want {
+				t.Errorf("isMultiple(%v, %v) = %v, want %v", c.amount, c.tick, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRoundDownToTick(t *testing.T) {
+	cases := []struct {
+		name   string
+		amount *big.Int
+		tick   *big.Int
+		want   *big.Int
+	}{
+		{"unset tick leaves amount unchanged", big.NewInt(105), nil, big.NewInt(105)},
+		{"exact multiple unchanged", big.NewInt(100), big.NewInt(10), big.NewInt(100)},
+		{"rounds down to the nearest tick", big.NewInt(105), big.NewInt(10), big.NewInt(100)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := roundDownToTick(c.amount, c.tick); got.Cmp(c.want) != 0 {
+				t.Errorf("roundDownToTick(%v, %v) = %v, want %v", c.amount, c.tick, got, c.want)
+			}
+		})
+	}
+}
+
+func TestPairMetadataValidateOrder(t *testing.T) {
+	m := &PairMetadata{
+		AmountTickSize: big.NewInt(10),
+		PriceTickSize:  big.NewInt(5),
+		MinNotional:    big.NewInt(50),
+	}
+
+	cases := []struct {
+		name       string
+		amountBuy  int64
+		amountSell int64
+		want       string
+	}{
+		{"passes every constraint", 10, 100, ""},
+		{"violates lot size", 10, 105, RejectLotSize},
+		{"violates tick size", 11, 100, RejectTickSize},
+		{"violates min notional", 10, 40, RejectMinNotional},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := &Order{AmountBuy: big.NewInt(c.amountBuy), AmountSell: big.NewInt(c.amountSell)}
+			if got := m.ValidateOrder(o); got != c.want {
+				t.Errorf("ValidateOrder() = %q, want %q", got, c.want)
+			}
+		})
+	}
+
+	t.Run("nil metadata never rejects", func(t *testing.T) {
+		var m *PairMetadata
+		o := &Order{AmountBuy: big.NewInt(11), AmountSell: big.NewInt(3)}
+		if got := m.ValidateOrder(o); got != "" {
+			t.Errorf("ValidateOrder() = %q, want \"\"", got)
+		}
+	})
+}
@@ -0,0 +1,247 @@
+package dex
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// BATCH_INSERTED is the message type for one ingestion batch's ordered
+// fills, carried in a BatchInsertedPayload. The pipeline itself hands each
+// batch's fills to its BatchSink and does not send this message.
+const BATCH_INSERTED MessageType = "BATCH_INSERTED"
+
+// Default tuning for the order ingestion pipeline.
+const (
+	DefaultIngestBatchSize   = 256
+	DefaultIngestMaxLatency  = 5 * time.Millisecond
+	DefaultIngestWorkerCount = 4
+)
+
+// BatchInsertedPayload carries the ordered fills produced by inserting one
+// ingestion batch into the book.
+type BatchInsertedPayload struct {
+	Fills []*Matches
+}
+
+// BatchSink receives the ordered fills produced by inserting one ingestion
+// batch into the book. It is process-wide and keyed only by engine, never by
+// socket, so fills from one client's batch can never be delivered to another
+// client's websocket connection.
+type BatchSink func(fills []*Matches)
+
+// defaultBatchSink is used when no explicit BatchSink is supplied to
+// NewIngestPipeline. It only logs; a deployment that wants batch fills
+// persisted (to a database, message bus, etc.) should construct the
+// pipeline with its own sink instead.
+func defaultBatchSink(fills []*Matches) {
+	log.Printf("Inserted batch of %d fills", len(fills))
+}
+
+// IngestPipeline batches incoming orders from every socket into a shared
+// bounded channel, validates signatures for a batch in parallel, and
+// acquires the book's write lock once per batch to insert every order
+// atomically. This raises throughput under bursty inflow while keeping
+// matching sequentially consistent.
+type IngestPipeline struct {
+	engine      *Engine
+	sink        BatchSink
+	ordersIn    chan *Order
+	batchSize   int
+	maxLatency  time.Duration
+	workerCount int
+
+	wg sync.WaitGroup
+}
+
+// NewIngestPipeline creates an IngestPipeline draining into engine. A zero
+// batchSize, maxLatency or workerCount falls back to the package default,
+// and a nil sink falls back to defaultBatchSink.
+func NewIngestPipeline(engine *Engine, sink BatchSink, batchSize int, maxLatency time.Duration, workerCount int) *IngestPipeline {
+	if batchSize <= 0 {
+		batchSize = DefaultIngestBatchSize
+	}
+	if maxLatency <= 0 {
+		maxLatency = DefaultIngestMaxLatency
+	}
+	if workerCount <= 0 {
+		workerCount = DefaultIngestWorkerCount
+	}
+	if sink == nil {
+		sink = defaultBatchSink
+	}
+
+	return &IngestPipeline{
+		engine:      engine,
+		sink:        sink,
+		ordersIn:    make(chan *Order, batchSize*workerCount),
+		batchSize:   batchSize,
+		maxLatency:  maxLatency,
+		workerCount: workerCount,
+	}
+}
+
+// Submit pushes o onto the shared ingestion channel, applying backpressure
+// to the calling socket if the channel is full.
+func (p *IngestPipeline) Submit(o *Order) {
+	p.ordersIn <- o
+}
+
+var (
+	enginePipelines   = map[*Engine]*IngestPipeline{}
+	enginePipelinesMu sync.Mutex
+)
+
+// pipelineFor returns the shared ingestion pipeline for engine, constructing
+// and starting it with defaultBatchSink on first use. Sockets submit new
+// orders to the engine only through this accessor, so PLACE_ORDER always has a
+// running pipeline to land on instead of depending on a separate
+// server-startup wiring step. The pipeline is keyed only by engine, never by
+// socket, so it can't end up bound to whichever socket happens to call this
+// first.
+func pipelineFor(engine *Engine) *IngestPipeline {
+	enginePipelinesMu.Lock()
+	defer enginePipelinesMu.Unlock()
+
+	p, ok := enginePipelines[engine]
+	if !ok {
+		p = NewIngestPipeline(engine, defaultBatchSink, DefaultIngestBatchSize, DefaultIngestMaxLatency, DefaultIngestWorkerCount)
+		p.Start(context.Background())
+		enginePipelines[engine] = p
+	}
+
+	return p
+}
+
+// Start launches the worker pool that drains ordersIn. Call Stop to drain
+// and shut it down.
+func (p *IngestPipeline) Start(ctx context.Context) {
+	for i := 0; i < p.workerCount; i++ {
+		p.wg.Add(1)
+		go p.worker(ctx)
+	}
+}
+
+// Stop closes the ingestion channel and waits for every worker to flush its
+// remaining batch.
+func (p *IngestPipeline) Stop() {
+	close(p.ordersIn)
+	p.wg.Wait()
+}
+
+// worker groups arrivals off ordersIn into batches of up to batchSize,
+// flushing early whenever maxLatency elapses since the last flush.
+func (p *IngestPipeline) worker(ctx context.Context) {
+	defer p.wg.Done()
+
+	timer := time.NewTimer(p.maxLatency)
+	defer timer.Stop()
+
+	batch := make([]*Order, 0, p.batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		p.insertBatch(ctx, batch)
+		batch = make([]*Order, 0, p.batchSize)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return
+		case o, ok := <-p.ordersIn:
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, o)
+			if len(batch) >= p.batchSize {
+				flush()
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(p.maxLatency)
+			}
+		case <-timer.C:
+			flush()
+			timer.Reset(p.maxLatency)
+		}
+	}
+}
+
+// insertBatch validates every order's signature in parallel, then acquires
+// the book's write lock once to insert the whole batch, and passes the
+// ordered fills to the pipeline's BatchSink in a single call. It does not
+// build or send a BATCH_INSERTED message itself.
+func (p *IngestPipeline) insertBatch(ctx context.Context, batch []*Order) {
+	valid := make([]*Order, len(batch))
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, o := range batch {
+		i, o := i, o
+		g.Go(func() error {
+			if !o.VerifySignature() {
+				log.Printf("Error: order %d failed signature validation, dropping from batch", o.Id)
+				return nil
+			}
+
+			select {
+			case <-gctx.Done():
+				return gctx.Err()
+			default:
+				valid[i] = o
+				return nil
+			}
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		log.Printf("Error: batch signature validation aborted: %v", err)
+		return
+	}
+
+	orders := make([]*Order, 0, len(valid))
+	for _, o := range valid {
+		if o != nil {
+			orders = append(orders, o)
+		}
+	}
+	if len(orders) == 0 {
+		return
+	}
+
+	fills := p.engine.InsertBatch(orders)
+	if len(fills) == 0 {
+		return
+	}
+
+	p.sink(fills)
+}
+
+// InsertBatch acquires the book's write lock once and inserts every order in
+// orders in turn, returning the ordered list of fills produced, so a whole
+// ingestion batch commits atomically instead of one write-lock acquisition
+// per order.
+func (e *Engine) InsertBatch(orders []*Order) []*Matches {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	fills := make([]*Matches, 0, len(orders))
+	for _, o := range orders {
+		if m := e.insertLocked(o); m != nil {
+			fills = append(fills, m)
+		}
+	}
+
+	return fills
+}
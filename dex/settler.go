@@ -0,0 +1,194 @@
+package dex
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ExchangeContract is the subset of the generated Exchange contract binding
+// that Settler needs in order to submit a matched trade on-chain.
+type ExchangeContract interface {
+	Trade(opts *bind.TransactOpts, trade *Trade) (*types.Transaction, error)
+}
+
+// Settler builds and submits the on-chain Exchange trade transaction for a
+// successful match, tracks confirmations, and emits TRADE_TX_SUCCESS or
+// TRADE_TX_ERROR onto the trade's event channel.
+type Settler struct {
+	client        *ethclient.Client
+	exchange      ExchangeContract
+	wallet        *Wallet
+	confirmations uint64
+
+	nonceMu    sync.Mutex
+	nonce      uint64
+	nonceKnown bool
+}
+
+// NewSettler returns a Settler that waits for confirmations block
+// confirmations before declaring a trade settled.
+func NewSettler(client *ethclient.Client, exchange ExchangeContract, wallet *Wallet, confirmations uint64) *Settler {
+	return &Settler{
+		client:        client,
+		exchange:      exchange,
+		wallet:        wallet,
+		confirmations: confirmations,
+	}
+}
+
+// Settle submits the on-chain trade transaction for t using EIP-1559 gas
+// pricing (falling back to legacy SuggestGasPrice when the node doesn't
+// support it) and a locally cached nonce so many trades can be dispatched in
+// parallel without racing PendingNonceAt. It returns once the transaction
+// has been submitted; confirmation is tracked asynchronously.
+func (s *Settler) Settle(ctx context.Context, t *Trade) error {
+	nonce, err := s.nextNonce(ctx)
+	if err != nil {
+		return err
+	}
+
+	tipCap, feeCap, err := s.suggestGasPricing(ctx)
+	if err != nil {
+		return err
+	}
+
+	auth, err := bind.NewKeyedTransactorWithChainID(s.wallet.PrivateKey, s.wallet.ChainID)
+	if err != nil {
+		return err
+	}
+	auth.Context = ctx
+	auth.Nonce = big.NewInt(int64(nonce))
+	auth.GasTipCap = tipCap
+	auth.GasFeeCap = feeCap
+
+	tx, err := s.exchange.Trade(auth, t)
+	if err != nil {
+		s.emitTradeTxError(t, err)
+		return err
+	}
+
+	go s.watchConfirmations(ctx, t, tx)
+	return nil
+}
+
+// nextNonce returns the next nonce to use for the settlement wallet,
+// fetching the starting point from the node once and then incrementing a
+// local cache so concurrent Settle calls never reuse a nonce.
+func (s *Settler) nextNonce(ctx context.Context) (uint64, error) {
+	s.nonceMu.Lock()
+	defer s.nonceMu.Unlock()
+
+	if !s.nonceKnown {
+		n, err := s.client.PendingNonceAt(ctx, s.wallet.Address)
+		if err != nil {
+			return 0, err
+		}
+		s.nonce = n
+		s.nonceKnown = true
+	}
+
+	nonce := s.nonce
+	s.nonce++
+	return nonce, nil
+}
+
+// suggestGasPricing returns the tip cap and fee cap to use for a 1559
+// transaction, falling back to legacy SuggestGasPrice (used as both tip and
+// fee cap) when the node doesn't support SuggestGasTipCap.
+func (s *Settler) suggestGasPricing(ctx context.Context) (tipCap, feeCap *big.Int, err error) {
+	tipCap, err = s.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		gasPrice, legacyErr := s.client.SuggestGasPrice(ctx)
+		if legacyErr != nil {
+			return nil, nil, legacyErr
+		}
+		return gasPrice, gasPrice, nil
+	}
+
+	head, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	feeCap = new(big.Int).Add(tipCap, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	return tipCap, feeCap, nil
+}
+
+// watchConfirmations follows new block headers until tx has accumulated
+// s.confirmations confirmations, emitting TRADE_TX_SUCCESS, or
+// TRADE_TX_ERROR if the transaction failed. The decoded revert reason is
+// logged; the TRADE_TX_ERROR event carries only the trade.
+func (s *Settler) watchConfirmations(ctx context.Context, t *Trade, tx *types.Transaction) {
+	heads := make(chan *types.Header)
+	sub, err := s.client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		log.Printf("Error: Settler failed to subscribe to new heads: %v", err)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	var minedAt uint64
+
+	for {
+		select {
+		case err := <-sub.Err():
+			log.Printf("Error: Settler header subscription failed: %v", err)
+			return
+		case head := <-heads:
+			receipt, err := s.client.TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				continue
+			}
+
+			if minedAt == 0 {
+				minedAt = receipt.BlockNumber.Uint64()
+			}
+
+			if receipt.Status == types.ReceiptStatusFailed {
+				reason := s.decodeRevertReason(ctx, tx, receipt)
+				s.emitTradeTxError(t, fmt.Errorf("trade reverted: %s", reason))
+				return
+			}
+
+			if head.Number.Uint64()-minedAt+1 >= s.confirmations {
+				s.emitTradeTxSuccess(t)
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// decodeRevertReason replays tx at the block it was mined in to recover the
+// revert reason string returned by the node.
+func (s *Settler) decodeRevertReason(ctx context.Context, tx *types.Transaction, receipt *types.Receipt) string {
+	msg := ethereum.CallMsg{
+		To:   tx.To(),
+		Data: tx.Data(),
+	}
+
+	_, err := s.client.CallContract(ctx, msg, receipt.BlockNumber)
+	if err == nil {
+		return "unknown"
+	}
+
+	return err.Error()
+}
+
+func (s *Settler) emitTradeTxSuccess(t *Trade) {
+	t.events <- &Event{eventType: TRADE_TX_SUCCESS, payload: t}
+}
+
+func (s *Settler) emitTradeTxError(t *Trade, err error) {
+	log.Printf("Error: trade settlement failed: %v", err)
+	t.events <- &Event{eventType: TRADE_TX_ERROR, payload: t}
+}
@@ -1,9 +1,12 @@
 package dex
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/websocket"
 )
 
@@ -16,6 +19,24 @@ type Socket struct {
 	messagesIn  chan *Message
 	messagesOut chan *Message
 	events      chan *Event
+	validator   Validator
+	settler     *Settler
+	maker       common.Address
+
+	matchSubscribers []func(*Matches)
+}
+
+// SetMaker records the wallet address this socket has authenticated as,
+// established out-of-band (typically by verifying a signed handshake
+// message) before a CANCEL_ORDERS_BATCH request is scoped to it.
+func (s *Socket) SetMaker(maker common.Address) {
+	s.maker = maker
+}
+
+// Subscribe registers fn to be called with every validated Matches result,
+// used by execution algorithms such as TWAPExecution to track their fills.
+func (s *Socket) Subscribe(fn func(*Matches)) {
+	s.matchSubscribers = append(s.matchSubscribers, fn)
 }
 
 // listenToMessagesIn reads incoming messages from the websocket connection
@@ -42,8 +63,12 @@ func (s *Socket) handleMessagesIn() {
 		switch m.MessageType {
 		case PLACE_ORDER:
 			s.placeOrder(m.Payload)
+		case PLACE_ORDERS_BATCH:
+			s.placeOrdersBatch(m.Payload)
 		case CANCEL_ORDER:
 			s.cancelOrder(m.Payload)
+		case CANCEL_ORDERS_BATCH:
+			s.cancelOrdersBatch(m.Payload)
 		case SIGNED_DATA:
 			s.executeOrder(m.Payload)
 		case DONE:
@@ -64,11 +89,11 @@ func (s *Socket) handleMessagesOut() {
 			order := e.payload.(*Order)
 			s.sendOrderPlaced(order)
 		case ORDER_PARTIALLY_FILLED:
-			order := e.payload.(*TradePayload)
-			s.sendOrderPartiallyFilled(order)
+			matches := e.payload.(*Matches)
+			s.handleMatches(matches, s.sendOrderPartiallyFilled)
 		case ORDER_FILLED:
-			payload := e.payload.(*TradePayload)
-			s.sendOrderFilled(payload)
+			matches := e.payload.(*Matches)
+			s.handleMatches(matches, s.sendOrderFilled)
 		case ORDER_CANCELED:
 			order := e.payload.(*Order)
 			s.sendOrderCanceled(order)
@@ -83,7 +108,7 @@ func (s *Socket) handleMessagesOut() {
 		case ORDER_TX_ERROR:
 			log.Printf("Order TX error")
 			order := e.payload.(*Order)
-			s.sendOrderTxSuccess(order)
+			s.sendOrderTxError(order)
 		case TRADE_EXECUTED:
 			log.Printf("Trade executed")
 			trade := e.payload.(*Trade)
@@ -95,7 +120,7 @@ func (s *Socket) handleMessagesOut() {
 		case TRADE_TX_ERROR:
 			log.Printf("Trade Tx Error")
 			trade := e.payload.(*Trade)
-			s.sendTradeTxSuccess(trade)
+			s.sendTradeTxError(trade)
 		case DONE:
 		default:
 			panic("Unknown action type")
@@ -110,8 +135,92 @@ func (s *Socket) placeOrder(p Payload) {
 	o.Decode(payload)
 
 	o.events = s.events
-	if err := s.server.engine.AddOrder(o); err != nil {
-		log.Printf("Error: Failed processing order: %v", err)
+
+	if reason := s.rejectReason(o); reason != "" {
+		if err := s.sendOrderRejected(o, reason); err != nil {
+			log.Printf("Error: %v", err)
+		}
+		return
+	}
+
+	s.pipeline().Submit(o)
+}
+
+// pipeline returns the ingestion pipeline shared by every socket backed by
+// the same matching engine.
+func (s *Socket) pipeline() *IngestPipeline {
+	return pipelineFor(s.server.engine)
+}
+
+// rejectReason returns the reason code for the first constraint o violates
+// (expiry, signature, or the pair's tick-size/lot-size/min-notional rules),
+// or "" if o is good to submit to the engine.
+func (s *Socket) rejectReason(o *Order) string {
+	if o.Expires != nil && o.Expires.Sign() > 0 && o.Expires.Int64() < time.Now().Unix() {
+		return RejectExpired
+	}
+
+	if s.validator != nil {
+		if err := s.validator.ValidateSignature(o); err != nil {
+			return RejectBadSignature
+		}
+	}
+
+	return s.server.engine.PairMetadata(o.PairID).ValidateOrder(o)
+}
+
+// sendOrderRejected creates an ORDER_REJECTED message carrying the reason
+// code and writes it into the websocket connection.
+func (s *Socket) sendOrderRejected(o *Order, reason string) error {
+	p := &OrderRejectedPayload{Order: o, Reason: reason}
+	m := &Message{MessageType: ORDER_REJECTED, Payload: p}
+
+	if err := s.connection.WriteJSON(&m); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// placeOrdersBatch decodes a batch of orders, applying the same
+// expiry/signature/tick-lot-notional validation as a single PLACE_ORDER and
+// submitting everything that passes through the shared ingestion pipeline,
+// so a client can't dodge per-order validation by using the batch endpoint
+// instead of PLACE_ORDER.
+func (s *Socket) placeOrdersBatch(p Payload) {
+	rawOrders := p.(map[string]interface{})["orders"].([]interface{})
+
+	for _, raw := range rawOrders {
+		o := &Order{}
+		o.Decode(raw.(map[string]interface{}))
+		o.events = s.events
+
+		if reason := s.rejectReason(o); reason != "" {
+			if err := s.sendOrderRejected(o, reason); err != nil {
+				log.Printf("Error: %v", err)
+			}
+			continue
+		}
+
+		s.pipeline().Submit(o)
+	}
+}
+
+// cancelOrdersBatch decodes a CANCEL_ORDERS_BATCH message and gracefully
+// cancels every resting order the requesting socket's authenticated maker
+// owns for the given pair, used by market-makers to flatten their own book
+// on disconnect. A socket that hasn't authenticated a maker address cannot
+// cancel anyone's orders.
+func (s *Socket) cancelOrdersBatch(p Payload) {
+	if s.maker == (common.Address{}) {
+		log.Printf("Error: CANCEL_ORDERS_BATCH rejected, socket has no authenticated maker")
+		return
+	}
+
+	pairID := int64(p.(map[string]interface{})["pairId"].(float64))
+
+	if err := s.server.engine.GracefulCancelAll(pairID, s.maker); err != nil {
+		log.Printf("Error: %v", err)
 	}
 }
 
@@ -145,6 +254,13 @@ func (s *Socket) executeOrder(p Payload) {
 	err := s.server.engine.ExecuteOrder(t)
 	if err != nil {
 		log.Printf("Error: %v", err)
+		return
+	}
+
+	if s.settler != nil {
+		if err := s.settler.Settle(context.Background(), t); err != nil {
+			log.Printf("Error: %v", err)
+		}
 	}
 
 	fmt.Printf("\nLOG: Executing order. Payload:\n%v\n\n", t)
@@ -163,10 +279,36 @@ func (s *Socket) sendOrderPlaced(o *Order) error {
 	return nil
 }
 
+// handleMatches validates every maker order in matches, reports the invalid
+// ones upstream via INVALIDATE_MAKER_ORDERS so they can be pulled from the
+// book, and forwards only the valid subset to send to the taker.
+func (s *Socket) handleMatches(matches *Matches, send func(*Matches) error) {
+	validMatches, invalidMatches := s.validateMatches(matches)
+
+	if len(invalidMatches) > 0 {
+		if err := s.sendInvalidateMakerOrders(invalidMatches); err != nil {
+			log.Printf("Error: %v", err)
+		}
+	}
+
+	if len(validMatches.MakerOrders) == 0 {
+		return
+	}
+
+	if err := send(validMatches); err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	for _, fn := range s.matchSubscribers {
+		fn(validMatches)
+	}
+}
+
 // sendOrderFilledMessage creates an ORDER_FILLED messages and writes it into the websocket connection
-func (s *Socket) sendOrderFilled(p *TradePayload) error {
-	m := &Message{MessageType: ORDER_FILLED, Payload: p}
-	if err := s.connection.WriteJSON(&m); err != nil {
+func (s *Socket) sendOrderFilled(m *Matches) error {
+	p := &MatchesPayload{Matches: m}
+	msg := &Message{MessageType: ORDER_FILLED, Payload: p}
+	if err := s.connection.WriteJSON(&msg); err != nil {
 		return err
 	}
 
@@ -175,10 +317,11 @@ func (s *Socket) sendOrderFilled(p *TradePayload) error {
 }
 
 // sendOrderPartiallyFilledMessage creates and ORDER_PARTIALLY_FILLED message and writes it into the websocket connection
-func (s *Socket) sendOrderPartiallyFilled(p *TradePayload) error {
+func (s *Socket) sendOrderPartiallyFilled(m *Matches) error {
 	fmt.Printf("Send order partially filled message")
-	m := &Message{MessageType: ORDER_PARTIALLY_FILLED, Payload: p}
-	if err := s.connection.WriteJSON(&m); err != nil {
+	p := &MatchesPayload{Matches: m}
+	msg := &Message{MessageType: ORDER_PARTIALLY_FILLED, Payload: p}
+	if err := s.connection.WriteJSON(&msg); err != nil {
 		return err
 	}
 
@@ -0,0 +1,260 @@
+package dex
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// TWAP_STARTED, TWAP_PROGRESS and TWAP_DONE let a client kick off a
+// time-weighted average price execution with a single command instead of
+// hand-slicing a large order into child orders.
+const (
+	TWAP_STARTED  MessageType = "TWAP_STARTED"
+	TWAP_PROGRESS MessageType = "TWAP_PROGRESS"
+	TWAP_DONE     MessageType = "TWAP_DONE"
+)
+
+// Side indicates whether a TWAPExecution is working the buy or sell side of
+// the book.
+type Side int
+
+const (
+	Buy Side = iota
+	Sell
+)
+
+// TWAPPayload reports the state of a TWAP execution over the wire.
+type TWAPPayload struct {
+	PairID    int64
+	Side      Side
+	Remaining int64
+	Total     int64
+}
+
+// TWAPExecution slices a large order into child orders spread evenly over a
+// duration and streams them through PLACE_ORDER at a rate no faster than one
+// slice per sliceInterval, re-quoting the remaining size as fills come in.
+type TWAPExecution struct {
+	factory       *OrderFactory
+	socket        *Socket
+	pair          *TokenPair
+	side          Side
+	totalAmount   int64
+	priceLimit    int64
+	sliceInterval time.Duration
+	sliceAmount   int64
+
+	mu        sync.Mutex
+	remaining int64
+	limiter   *rate.Limiter
+	current   *Order
+	done      chan struct{}
+}
+
+// NewTWAPExecution creates a TWAPExecution that works totalAmount of pair
+// over duration, sending a child order at most every sliceInterval and never
+// crossing priceLimit.
+func NewTWAPExecution(f *OrderFactory, s *Socket, pair *TokenPair, side Side, totalAmount, priceLimit int64, duration, sliceInterval time.Duration) *TWAPExecution {
+	sliceCount := int64(duration / sliceInterval)
+	if sliceCount < 1 {
+		sliceCount = 1
+	}
+
+	sliceAmount := totalAmount / sliceCount
+	if sliceAmount < 1 {
+		sliceAmount = totalAmount
+	}
+
+	return &TWAPExecution{
+		factory:       f,
+		socket:        s,
+		pair:          pair,
+		side:          side,
+		totalAmount:   totalAmount,
+		priceLimit:    priceLimit,
+		sliceInterval: sliceInterval,
+		sliceAmount:   sliceAmount,
+		remaining:     totalAmount,
+		limiter:       rate.NewLimiter(rate.Every(sliceInterval), 1),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start sends TWAP_STARTED, subscribes to fills on the socket so each slice
+// re-quotes the remaining size, and runs the slicing loop until the full
+// amount is worked or ctx is canceled.
+func (t *TWAPExecution) Start(ctx context.Context) {
+	t.socket.Subscribe(t.OnFill)
+
+	if err := t.sendStarted(); err != nil {
+		log.Printf("Error: TWAP failed to send TWAP_STARTED: %v", err)
+	}
+
+	go t.run(ctx)
+}
+
+// Done returns a channel that is closed once fills have covered the total
+// amount or the context passed to Start is canceled. Stop does not close it.
+func (t *TWAPExecution) Done() <-chan struct{} {
+	return t.done
+}
+
+func (t *TWAPExecution) run(ctx context.Context) {
+	defer close(t.done)
+
+	for t.remainingAmount() > 0 {
+		if err := t.limiter.Wait(ctx); err != nil {
+			t.cancelCurrent()
+			return
+		}
+
+		// Cancel-and-replace: supersede the previous slice on every tick,
+		// whether it rested unfilled or already filled, so at most one
+		// child order is ever resting on the book and OnFill can never be
+		// asked to account for fills against an abandoned order.
+		t.cancelCurrent()
+
+		amount := t.sliceAmount
+		if remaining := t.remainingAmount(); amount > remaining {
+			amount = remaining
+		}
+
+		o, err := t.placeSlice(amount)
+		if err != nil {
+			log.Printf("Error: TWAP slice failed to place: %v", err)
+			continue
+		}
+
+		t.setCurrent(o)
+		if err := t.sendProgress(); err != nil {
+			log.Printf("Error: TWAP failed to send TWAP_PROGRESS: %v", err)
+		}
+	}
+
+	t.cancelCurrent()
+
+	if err := t.sendDone(); err != nil {
+		log.Printf("Error: TWAP failed to send TWAP_DONE: %v", err)
+	}
+}
+
+// placeSlice builds one child order for amount, buying or selling the
+// pair's base token depending on side, and submits it to the engine the
+// same way the socket ingests a real client order. The counter-token amount
+// is derived from amount × priceLimit so priceLimit actually bounds the
+// price of every slice instead of a flat quote amount.
+func (t *TWAPExecution) placeSlice(amount int64) (*Order, error) {
+	var o *Order
+	var err error
+
+	quoteAmount := amount * t.priceLimit
+
+	if t.side == Sell {
+		o, err = t.factory.NewOrder(t.pair.QuoteToken, quoteAmount, t.pair.BaseToken, amount)
+	} else {
+		o, err = t.factory.NewOrder(t.pair.BaseToken, amount, t.pair.QuoteToken, quoteAmount)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	o.events = t.socket.events
+	t.socket.pipeline().Submit(o)
+	return o, nil
+}
+
+// OnFill is called with every validated Matches result on the socket. It
+// deducts the current child order's filled amount from the outstanding
+// size so the next slice re-quotes only the remainder.
+func (t *TWAPExecution) OnFill(m *Matches) {
+	if m.TakerOrder == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.current == nil || m.TakerOrder.Id != t.current.Id {
+		return
+	}
+
+	for _, trade := range m.Trades {
+		t.remaining -= trade.Amount.Int64()
+	}
+}
+
+// Stop cancels the outstanding child order, if any. It does not end the
+// slicing loop: until the context passed to Start is canceled, the next
+// tick places a new slice.
+func (t *TWAPExecution) Stop() {
+	t.cancelCurrent()
+}
+
+// cancelCurrent cancels and clears the outstanding child order, if any. It
+// clears t.current before the cancel reaches the engine, so from then on
+// OnFill ignores fills against that order, including fills that race the
+// cancel.
+func (t *TWAPExecution) cancelCurrent() {
+	t.mu.Lock()
+	current := t.current
+	t.current = nil
+	t.mu.Unlock()
+
+	if current == nil {
+		return
+	}
+
+	oc, err := t.factory.NewOrderCancel(current)
+	if err != nil {
+		log.Printf("Error: TWAP failed to build cancel order: %v", err)
+		return
+	}
+
+	if err := t.socket.server.engine.CancelOrder(oc); err != nil {
+		log.Printf("Error: TWAP failed to cancel child order: %v", err)
+	}
+}
+
+func (t *TWAPExecution) remainingAmount() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.remaining
+}
+
+func (t *TWAPExecution) setCurrent(o *Order) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current = o
+}
+
+func (t *TWAPExecution) payload() *TWAPPayload {
+	t.mu.Lock()
+	remaining := t.remaining
+	t.mu.Unlock()
+
+	return &TWAPPayload{
+		PairID:    t.pair.ID,
+		Side:      t.side,
+		Remaining: remaining,
+		Total:     t.totalAmount,
+	}
+}
+
+func (t *TWAPExecution) sendStarted() error {
+	m := &Message{MessageType: TWAP_STARTED, Payload: t.payload()}
+	return t.socket.connection.WriteJSON(&m)
+}
+
+func (t *TWAPExecution) sendProgress() error {
+	m := &Message{MessageType: TWAP_PROGRESS, Payload: t.payload()}
+	return t.socket.connection.WriteJSON(&m)
+}
+
+func (t *TWAPExecution) sendDone() error {
+	m := &Message{MessageType: TWAP_DONE, Payload: t.payload()}
+	return t.socket.connection.WriteJSON(&m)
+}
@@ -0,0 +1,80 @@
+package dex
+
+import "log"
+
+// INVALIDATE_MAKER_ORDERS is emitted upstream whenever a match contains a
+// maker order that failed validation, so the order can be pulled from the
+// book instead of being signaled to the taker as a fill.
+const INVALIDATE_MAKER_ORDERS MessageType = "INVALIDATE_MAKER_ORDERS"
+
+// Matches groups a taker order together with the maker orders and trades it
+// matched against. It replaces the old single TradePayload shape so the
+// socket layer can validate each maker order before the taker is asked to
+// sign against it.
+type Matches struct {
+	TakerOrder  *Order
+	MakerOrders []*Order
+	Trades      []*Trade
+}
+
+// MakerOrdersPayload wraps the maker orders carried by an
+// INVALIDATE_MAKER_ORDERS message.
+type MakerOrdersPayload struct {
+	Orders []*Order
+}
+
+// MatchesPayload wraps a Matches result for an ORDER_PARTIALLY_FILLED or
+// ORDER_FILLED message.
+type MatchesPayload struct {
+	Matches *Matches
+}
+
+// Validator checks that a maker order is still safe to fill before the
+// taker is asked to sign against it. Implementations typically check the
+// maker's on-chain balance and the validity of its signature.
+type Validator interface {
+	ValidateBalance(o *Order) error
+	ValidateSignature(o *Order) error
+}
+
+// validateMatches splits a Matches result into the maker orders (and their
+// paired trades) that are still good to fill, and the maker orders that must
+// be invalidated because they failed balance or signature validation.
+func (s *Socket) validateMatches(m *Matches) (valid *Matches, invalid []*Order) {
+	if s.validator == nil {
+		return m, nil
+	}
+
+	valid = &Matches{TakerOrder: m.TakerOrder}
+
+	for i, maker := range m.MakerOrders {
+		if err := s.validator.ValidateBalance(maker); err != nil {
+			log.Printf("Error: maker order %d failed balance validation: %v", maker.Id, err)
+			invalid = append(invalid, maker)
+			continue
+		}
+		if err := s.validator.ValidateSignature(maker); err != nil {
+			log.Printf("Error: maker order %d failed signature validation: %v", maker.Id, err)
+			invalid = append(invalid, maker)
+			continue
+		}
+
+		valid.MakerOrders = append(valid.MakerOrders, maker)
+		valid.Trades = append(valid.Trades, m.Trades[i])
+	}
+
+	return valid, invalid
+}
+
+// sendInvalidateMakerOrders notifies the upstream service that the given
+// maker orders failed validation and should be removed from the book.
+func (s *Socket) sendInvalidateMakerOrders(orders []*Order) error {
+	p := &MakerOrdersPayload{Orders: orders}
+	m := &Message{MessageType: INVALIDATE_MAKER_ORDERS, Payload: p}
+
+	if err := s.connection.WriteJSON(&m); err != nil {
+		return err
+	}
+
+	return nil
+}
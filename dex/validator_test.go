@@ -0,0 +1,75 @@
+package dex
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeValidator struct {
+	rejectBalance   map[uint64]bool
+	rejectSignature map[uint64]bool
+}
+
+func (v *fakeValidator) ValidateBalance(o *Order) error {
+	if v.rejectBalance[o.Id] {
+		return errors.New("insufficient balance")
+	}
+	return nil
+}
+
+func (v *fakeValidator) ValidateSignature(o *Order) error {
+	if v.rejectSignature[o.Id] {
+		return errors.New("bad signature")
+	}
+	return nil
+}
+
+func TestValidateMatchesNilValidator(t *testing.T) {
+	s := &Socket{}
+	taker := &Order{Id: 1}
+	maker := &Order{Id: 2}
+	m := &Matches{TakerOrder: taker, MakerOrders: []*Order{maker}, Trades: []*Trade{{}}}
+
+	valid, invalid := s.validateMatches(m)
+
+	if valid != m {
+		t.Errorf("validateMatches() with nil validator = %v, want m unchanged", valid)
+	}
+	if invalid != nil {
+		t.Errorf("validateMatches() with nil validator invalid = %v, want nil", invalid)
+	}
+}
+
+func TestValidateMatchesSplitsInvalidMakers(t *testing.T) {
+	taker := &Order{Id: 1}
+	ok := &Order{Id: 2}
+	badBalance := &Order{Id: 3}
+	badSignature := &Order{Id: 4}
+
+	okTrade := &Trade{}
+	m := &Matches{
+		TakerOrder:  taker,
+		MakerOrders: []*Order{ok, badBalance, badSignature},
+		Trades:      []*Trade{okTrade, {}, {}},
+	}
+
+	s := &Socket{validator: &fakeValidator{
+		rejectBalance:   map[uint64]bool{badBalance.Id: true},
+		rejectSignature: map[uint64]bool{badSignature.Id: true},
+	}}
+
+	valid, invalid := s.validateMatches(m)
+
+	if valid.TakerOrder != taker {
+		t.Errorf("validateMatches() valid.TakerOrder = %v, want %v", valid.TakerOrder, taker)
+	}
+	if len(valid.MakerOrders) != 1 || valid.MakerOrders[0] != ok {
+		t.Errorf("validateMatches() valid.MakerOrders = %v, want [%v]", valid.MakerOrders, ok)
+	}
+	if len(valid.Trades) != 1 || valid.Trades[0] != okTrade {
+		t.Errorf("validateMatches() valid.Trades = %v, want [%v]", valid.Trades, okTrade)
+	}
+	if len(invalid) != 2 {
+		t.Errorf("validateMatches() invalid = %v, want 2 orders", invalid)
+	}
+}